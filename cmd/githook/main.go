@@ -1,11 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/majikmate/assignment-pull-request/internal/checkout"
+	"github.com/majikmate/assignment-pull-request/internal/constants"
+	"github.com/majikmate/assignment-pull-request/internal/gate"
 	"github.com/majikmate/assignment-pull-request/internal/git"
 	"github.com/majikmate/assignment-pull-request/internal/protect"
 	"github.com/majikmate/assignment-pull-request/internal/workflow"
@@ -34,6 +38,21 @@ func main() {
 	assignmentPattern := workflowProcessor.AssignmentPattern()
 	protectedPathsPattern := workflowProcessor.ProtectedPathsPattern()
 
+	// Gate commits and pushes to locked assignment branches
+	if isGatingHook(hookType) {
+		err := checkLockedBranches(hookType, repositoryRoot, workflowProcessor.LockedBranches())
+		if errors.Is(err, gate.ErrBranchLocked) {
+			fmt.Fprintf(os.Stderr, "🔒 %v\n", err)
+			fmt.Fprintf(os.Stderr, "   This check is advisory and enforced by client-side git hooks only.\n")
+			os.Exit(1)
+		}
+		if err != nil {
+			// Fail open: only a locked branch may block the git operation
+			log.Printf("Failed to check locked branches: %v", err)
+		}
+		return
+	}
+
 	// Handle sparse checkout only for post-checkout with branch checkout
 	if shouldProcessSparseCheckout(hookType) {
 		if len(assignmentPattern.Patterns()) > 0 {
@@ -56,7 +75,12 @@ func main() {
 			log.Printf("Protecting paths with protected paths patterns...")
 
 			// Create protect processor
-			protectProcessor := protect.New(repositoryRoot)
+			protectOptions, err := protectOptionsFromEnv()
+			if err != nil {
+				// Don't let a configuration typo disable path protection
+				log.Printf("Warning: ignoring invalid protect options: %v", err)
+			}
+			protectProcessor := protect.NewWithOptions(repositoryRoot, protectOptions)
 			err = protectProcessor.ProtectPaths(protectedPathsPattern)
 			if err != nil {
 				log.Printf("Failed to protect paths: %v", err)
@@ -122,3 +146,50 @@ func shouldProcessProtectedPaths(hookType string) bool {
 
 	return false
 }
+
+// isGatingHook determines if this hook can reject the git operation that triggered it
+func isGatingHook(hookType string) bool {
+	return hookType == "pre-commit" || hookType == "pre-push"
+}
+
+// checkLockedBranches rejects commits or pushes targeting locked assignment branches
+func checkLockedBranches(hookType, repositoryRoot string, lockedBranches []string) error {
+	if len(lockedBranches) == 0 {
+		return nil
+	}
+
+	gateProcessor := gate.New(repositoryRoot)
+	if hookType == "pre-push" {
+		return gateProcessor.CheckPush(lockedBranches, os.Stdin)
+	}
+	return gateProcessor.CheckCommit(lockedBranches)
+}
+
+// protectOptionsFromEnv builds protect options from environment variables
+// Invalid values are reported in the returned error and left at their defaults,
+// so the returned options are always usable.
+func protectOptionsFromEnv() (protect.Options, error) {
+	var opts protect.Options
+	var errs []error
+
+	if value := os.Getenv(constants.EnvProtectUmask); value != "" {
+		mask, err := strconv.ParseUint(value, 8, 32)
+		if err != nil || mask > 0777 {
+			errs = append(errs, fmt.Errorf("invalid %s value '%s': expected octal umask such as 0022", constants.EnvProtectUmask, value))
+		} else {
+			umask := int(mask)
+			opts.Umask = &umask
+		}
+	}
+
+	if value := os.Getenv(constants.EnvProtectTrackedOnly); value != "" {
+		trackedOnly, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s value '%s': expected true or false", constants.EnvProtectTrackedOnly, value))
+		} else {
+			opts.TrackedOnly = trackedOnly
+		}
+	}
+
+	return opts, errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/majikmate/assignment-pull-request/internal/constants"
+)
+
+func TestProtectOptionsFromEnv(t *testing.T) {
+	t.Setenv(constants.EnvProtectUmask, "0027")
+	t.Setenv(constants.EnvProtectTrackedOnly, "true")
+
+	opts, err := protectOptionsFromEnv()
+	if err != nil {
+		t.Fatalf("protectOptionsFromEnv() error = %v", err)
+	}
+	if opts.Umask == nil || *opts.Umask != 0027 {
+		t.Errorf("Umask = %v, want 0027", opts.Umask)
+	}
+	if !opts.TrackedOnly {
+		t.Errorf("TrackedOnly = false, want true")
+	}
+}
+
+func TestProtectOptionsFromEnvInvalidKeepsDefaults(t *testing.T) {
+	t.Setenv(constants.EnvProtectUmask, "0999")
+	t.Setenv(constants.EnvProtectTrackedOnly, "yes please")
+
+	opts, err := protectOptionsFromEnv()
+	if err == nil {
+		t.Fatal("protectOptionsFromEnv() error = nil, want error for invalid values")
+	}
+	if opts.Umask != nil {
+		t.Errorf("Umask = %o, want nil", *opts.Umask)
+	}
+	if opts.TrackedOnly {
+		t.Errorf("TrackedOnly = true, want false")
+	}
+}
+
+func TestProtectOptionsFromEnvPartiallyInvalid(t *testing.T) {
+	t.Setenv(constants.EnvProtectUmask, "bogus")
+	t.Setenv(constants.EnvProtectTrackedOnly, "true")
+
+	opts, err := protectOptionsFromEnv()
+	if err == nil {
+		t.Fatal("protectOptionsFromEnv() error = nil, want error for invalid umask")
+	}
+	if !opts.TrackedOnly {
+		t.Errorf("TrackedOnly = false, want valid value kept")
+	}
+}
@@ -2,12 +2,20 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
+// Output formats supported by --format
+const (
+	formatJSON = "json"
+	formatEnv  = "env"
+)
+
 // OCIImageInfo represents the OCI image metadata
 type OCIImageInfo struct {
 	Title         string `json:"title"`
@@ -24,21 +32,63 @@ type OCIImageInfo struct {
 	Licenses      string `json:"licenses"`
 }
 
+// envField binds an environment variable name to a field of OCIImageInfo
+type envField struct {
+	key   string
+	value *string
+}
+
+// envFields returns the field-to-env-var mapping shared by reading and writing env data
+func (info *OCIImageInfo) envFields() []envField {
+	return []envField{
+		{"OCI_IMAGE_TITLE", &info.Title},
+		{"OCI_IMAGE_DESCRIPTION", &info.Description},
+		{"OCI_IMAGE_VERSION", &info.Version},
+		{"OCI_IMAGE_REVISION", &info.Revision},
+		{"OCI_IMAGE_REF_NAME", &info.RefName},
+		{"OCI_IMAGE_SOURCE", &info.Source},
+		{"OCI_IMAGE_URL", &info.URL},
+		{"OCI_IMAGE_DOCUMENTATION", &info.Documentation},
+		{"OCI_IMAGE_CREATED", &info.Created},
+		{"OCI_IMAGE_AUTHORS", &info.Authors},
+		{"OCI_IMAGE_VENDOR", &info.Vendor},
+		{"OCI_IMAGE_LICENSES", &info.Licenses},
+	}
+}
+
 func main() {
+	format := flag.String("format", formatJSON, "output format: json or env")
+	flag.Parse()
+
+	if *format != formatJSON && *format != formatEnv {
+		fmt.Fprintf(os.Stderr, "Error: unsupported format %q (expected %q or %q)\n", *format, formatJSON, formatEnv)
+		os.Exit(1)
+	}
+
 	// Get container metadata from environment variables
 	info := getMetadataFromEnv()
 
-	// Don't write info.json if Version is empty
+	// Don't write info file if Version is empty
 	if info.Version == "" {
 		fmt.Println("No OCI metadata found")
 		return
 	}
 
-	// Convert to JSON
-	jsonData, err := json.MarshalIndent(info, "", "  ")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
-		os.Exit(1)
+	// Render in the requested format
+	var data []byte
+	var fileName string
+	switch *format {
+	case formatEnv:
+		data = []byte(formatEnvFile(info))
+		fileName = "info.env"
+	default:
+		jsonData, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+			os.Exit(1)
+		}
+		data = jsonData
+		fileName = "info.json"
 	}
 
 	// Ensure .devcontainer directory exists
@@ -48,9 +98,9 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Write to info.json file
-	outputPath := filepath.Join(devcontainerDir, "info.json")
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
+	// Write to output file
+	outputPath := filepath.Join(devcontainerDir, fileName)
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing to %s: %v\n", outputPath, err)
 		os.Exit(1)
 	}
@@ -59,25 +109,33 @@ func main() {
 
 	// Also print the metadata to stdout for verification
 	fmt.Println("\nContainer Metadata:")
-	fmt.Println(string(jsonData))
+	fmt.Println(strings.TrimRight(string(data), "\n"))
 }
 
 // getMetadataFromEnv reads metadata from environment variables
 func getMetadataFromEnv() OCIImageInfo {
-	return OCIImageInfo{
-		Title:         getEnvWithDefault("OCI_IMAGE_TITLE", ""),
-		Description:   getEnvWithDefault("OCI_IMAGE_DESCRIPTION", ""),
-		Version:       getEnvWithDefault("OCI_IMAGE_VERSION", ""),
-		Revision:      getEnvWithDefault("OCI_IMAGE_REVISION", ""),
-		RefName:       getEnvWithDefault("OCI_IMAGE_REF_NAME", ""),
-		Source:        getEnvWithDefault("OCI_IMAGE_SOURCE", ""),
-		URL:           getEnvWithDefault("OCI_IMAGE_URL", ""),
-		Documentation: getEnvWithDefault("OCI_IMAGE_DOCUMENTATION", ""),
-		Created:       getEnvWithDefault("OCI_IMAGE_CREATED", time.Now().UTC().Format(time.RFC3339)),
-		Authors:       getEnvWithDefault("OCI_IMAGE_AUTHORS", ""),
-		Vendor:        getEnvWithDefault("OCI_IMAGE_VENDOR", ""),
-		Licenses:      getEnvWithDefault("OCI_IMAGE_LICENSES", ""),
+	var info OCIImageInfo
+	for _, field := range info.envFields() {
+		*field.value = getEnvWithDefault(field.key, "")
 	}
+	if info.Created == "" {
+		info.Created = time.Now().UTC().Format(time.RFC3339)
+	}
+	return info
+}
+
+// formatEnvFile renders metadata as shell-sourceable KEY='value' lines
+func formatEnvFile(info OCIImageInfo) string {
+	var b strings.Builder
+	for _, field := range info.envFields() {
+		fmt.Fprintf(&b, "%s=%s\n", field.key, shellQuote(*field.value))
+	}
+	return b.String()
+}
+
+// shellQuote wraps a value in single quotes, escaping embedded single quotes for POSIX shells
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
 }
 
 // getEnvWithDefault returns the value of the environment variable or a default value
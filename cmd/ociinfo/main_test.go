@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatEnvFileRoundTrip(t *testing.T) {
+	values := map[string]string{
+		"spaces":    "My Course Image",
+		"quote":     "it's quoted",
+		"dollar":    "costs $HOME and ${PATH}",
+		"backticks": "run `id` now",
+		"newline":   "line one\nline two\n",
+		"mixed":     `a 'b' "c" \d $e ` + "`f`" + "\n\tg",
+		"empty":     "",
+	}
+
+	for name, value := range values {
+		t.Run(name, func(t *testing.T) {
+			info := OCIImageInfo{Title: value, Version: "1.0.0"}
+
+			envFile := filepath.Join(t.TempDir(), "info.env")
+			if err := os.WriteFile(envFile, []byte(formatEnvFile(info)), 0644); err != nil {
+				t.Fatalf("failed to write env file: %v", err)
+			}
+
+			output, err := exec.Command("sh", "-c", `. "$1"; printf %s "$OCI_IMAGE_TITLE"`, "sh", envFile).Output()
+			if err != nil {
+				t.Fatalf("failed to source env file: %v", err)
+			}
+
+			if string(output) != value {
+				t.Errorf("round-trip mismatch: got %q, want %q", string(output), value)
+			}
+		})
+	}
+}
+
+func TestFormatEnvFileUsesEnvMapping(t *testing.T) {
+	t.Setenv("OCI_IMAGE_VERSION", "2.1.0")
+	t.Setenv("OCI_IMAGE_LICENSES", "MIT")
+
+	info := getMetadataFromEnv()
+	want := "OCI_IMAGE_VERSION='2.1.0'\n"
+	if got := formatEnvFile(info); !strings.Contains(got, want) || !strings.Contains(got, "OCI_IMAGE_LICENSES='MIT'\n") {
+		t.Errorf("formatEnvFile() = %q, want lines for version and licenses", got)
+	}
+}
@@ -259,8 +259,9 @@ else
 fi
 
 # Create symbolic links for all post-* hooks that modify the working tree
+# and the pre-* hooks that gate commits/pushes to locked branches
 echo "🔗 Creating hook symlinks..."
-for hook in post-checkout post-merge post-rewrite post-applypatch post-commit post-reset; do
+for hook in post-checkout post-merge post-rewrite post-applypatch post-commit post-reset pre-commit pre-push; do
   sudo ln -sf protect-sync-hook "/etc/git/hooks/$hook"
   echo "   Linked $hook -> protect-sync-hook"
 done
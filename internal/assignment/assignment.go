@@ -106,6 +106,16 @@ func (ap *Processor) validateBranchNameUniqueness(assignments []Info) error {
 	return nil
 }
 
+// SearchOptions returns the path search options used to discover assignment folders
+func SearchOptions() paths.FindOptions {
+	return paths.FindOptions{
+		IncludeFiles:   false, // Only directories
+		IncludeDirs:    true,
+		LogPrefix:      "📁",
+		LogDescription: "assignment folders",
+	}
+}
+
 // findAssignments finds all assignment folders matching the processor's regex patterns
 func (ap *Processor) findAssignments() ([]string, error) {
 	fmt.Printf("📁 Searching for assignment folders...\n")
@@ -117,12 +127,7 @@ func (ap *Processor) findAssignments() ([]string, error) {
 	}
 
 	// Find all matching directories (only directories, not files)
-	info, err := pathsProcessor.FindWithOptions(paths.FindOptions{
-		IncludeFiles:   false, // Only directories
-		IncludeDirs:    true,
-		LogPrefix:      "📁",
-		LogDescription: "assignment folders",
-	})
+	info, err := pathsProcessor.FindWithOptions(SearchOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to find assignment paths: %w", err)
 	}
@@ -31,6 +31,12 @@ const (
 
 	// EnvDryRun is the environment variable for dry-run mode
 	EnvDryRun = "DRY_RUN"
+
+	// EnvProtectUmask is the environment variable for the octal umask applied during path protection
+	EnvProtectUmask = "PROTECT_UMASK"
+
+	// EnvProtectTrackedOnly is the environment variable for limiting path protection to tracked files
+	EnvProtectTrackedOnly = "PROTECT_TRACKED_ONLY"
 )
 
 // Common patterns and values
@@ -67,4 +73,7 @@ const (
 
 	// WorkflowProtectedPathsRegexKey is the YAML key for protected paths regex patterns
 	WorkflowProtectedPathsRegexKey = "protected-paths-regex"
+
+	// WorkflowLockedBranchesKey is the YAML key for branch names closed to further commits
+	WorkflowLockedBranchesKey = "locked-branches"
 )
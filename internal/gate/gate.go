@@ -0,0 +1,109 @@
+package gate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/majikmate/assignment-pull-request/internal/git"
+)
+
+// ErrBranchLocked is returned when a commit or push targets a locked branch
+// Any other error returned by the checks means the check itself could not run.
+var ErrBranchLocked = errors.New("assignment closed")
+
+// Processor handles gating checks that reject commits and pushes to locked assignment branches
+//
+// The checks run inside client-side git hooks and are therefore advisory only: a student can
+// bypass them with --no-verify or by removing the hooks. Server-side enforcement (e.g. branch
+// protection rules) is required for a hard deadline.
+type Processor struct {
+	repositoryRoot string
+	gitOps         *git.Operations
+}
+
+// New creates a new gating processor
+func New(repositoryRoot string) *Processor {
+	return &Processor{
+		repositoryRoot: repositoryRoot,
+		gitOps:         git.NewOperationsWithDir(false, repositoryRoot), // Use repository root as working directory
+	}
+}
+
+// CheckCommit rejects a commit if the currently checked out branch is locked
+func (p *Processor) CheckCommit(lockedBranches []string) error {
+	if len(lockedBranches) == 0 {
+		return nil
+	}
+
+	currentBranch, err := p.gitOps.GetCurrentBranch()
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	return checkBranch(currentBranch, lockedBranches)
+}
+
+// CheckPush rejects a push if any branch being pushed to is locked
+//
+// The refs being pushed are read from the pre-push hook's standard input, which contains
+// one "<local ref> <local sha> <remote ref> <remote sha>" line per ref. Only remote refs
+// under refs/heads/ are checked, so pushing tags is always allowed. If no refs are provided
+// at all, the currently checked out branch is checked instead.
+func (p *Processor) CheckPush(lockedBranches []string, refs io.Reader) error {
+	if len(lockedBranches) == 0 {
+		return nil
+	}
+
+	remoteRefs, err := parsePushedRefs(refs)
+	if err != nil {
+		return fmt.Errorf("failed to read pushed refs: %w", err)
+	}
+
+	if len(remoteRefs) == 0 {
+		return p.CheckCommit(lockedBranches)
+	}
+
+	for _, ref := range remoteRefs {
+		branch, ok := strings.CutPrefix(ref, "refs/heads/")
+		if !ok {
+			continue
+		}
+		if err := checkBranch(branch, lockedBranches); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkBranch returns an ErrBranchLocked error if branch is in the locked list
+func checkBranch(branch string, lockedBranches []string) error {
+	if slices.Contains(lockedBranches, branch) {
+		return fmt.Errorf("%w: branch '%s' is locked and no longer accepts commits or pushes", ErrBranchLocked, branch)
+	}
+	return nil
+}
+
+// parsePushedRefs extracts the remote refs from pre-push hook input
+func parsePushedRefs(refs io.Reader) ([]string, error) {
+	if refs == nil {
+		return nil, nil
+	}
+
+	var remoteRefs []string
+	scanner := bufio.NewScanner(refs)
+	for scanner.Scan() {
+		// Fields are: <local ref> <local sha> <remote ref> <remote sha>
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		remoteRefs = append(remoteRefs, fields[2])
+	}
+
+	return remoteRefs, scanner.Err()
+}
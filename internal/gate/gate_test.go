@@ -0,0 +1,112 @@
+package gate
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/majikmate/assignment-pull-request/internal/testutil"
+)
+
+func TestCheckCommit(t *testing.T) {
+	dir := testutil.InitRepo(t, nil)
+	processor := New(dir)
+	locked := []string{"assignment-1"}
+
+	if err := processor.CheckCommit(locked); err != nil {
+		t.Errorf("commit on unlocked branch rejected: %v", err)
+	}
+
+	testutil.RunGit(t, dir, "checkout", "-q", "-b", "assignment-1")
+	if err := processor.CheckCommit(locked); !errors.Is(err, ErrBranchLocked) {
+		t.Errorf("commit on locked branch: got %v, want ErrBranchLocked", err)
+	}
+
+	if err := processor.CheckCommit(nil); err != nil {
+		t.Errorf("commit without locked branches rejected: %v", err)
+	}
+}
+
+func TestCheckCommitUnbornBranchIsNotLocked(t *testing.T) {
+	processor := New(testutil.InitUnbornRepo(t))
+
+	err := processor.CheckCommit([]string{"assignment-1"})
+	if errors.Is(err, ErrBranchLocked) {
+		t.Errorf("unborn branch reported as locked: %v", err)
+	}
+}
+
+func TestCheckPush(t *testing.T) {
+	processor := New(testutil.InitRepo(t, nil))
+	locked := []string{"assignment-1"}
+
+	tests := []struct {
+		name   string
+		input  string
+		locked bool
+	}{
+		{"unlocked branch", "refs/heads/main abc refs/heads/main def\n", false},
+		{"locked remote branch", "refs/heads/main abc refs/heads/assignment-1 def\n", true},
+		{"locked among several", "refs/heads/main a refs/heads/main b\nrefs/heads/x c refs/heads/assignment-1 d\n", true},
+		{"tag named like locked branch", "refs/tags/assignment-1 abc refs/tags/assignment-1 def\n", false},
+		{"no refs falls back to current branch", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := processor.CheckPush(locked, strings.NewReader(tt.input))
+			if tt.locked && !errors.Is(err, ErrBranchLocked) {
+				t.Errorf("got %v, want ErrBranchLocked", err)
+			}
+			if !tt.locked && err != nil {
+				t.Errorf("got %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestCheckPushTagsFromLockedBranch(t *testing.T) {
+	dir := testutil.InitRepo(t, nil)
+	testutil.RunGit(t, dir, "checkout", "-q", "-b", "assignment-1")
+	processor := New(dir)
+	locked := []string{"assignment-1"}
+
+	if err := processor.CheckPush(locked, strings.NewReader("refs/tags/v1 abc refs/tags/v1 def\n")); err != nil {
+		t.Errorf("tag push from locked branch rejected: %v", err)
+	}
+	if err := processor.CheckPush(locked, strings.NewReader("")); !errors.Is(err, ErrBranchLocked) {
+		t.Errorf("push without refs from locked branch: got %v, want ErrBranchLocked", err)
+	}
+}
+
+func TestCheckBranch(t *testing.T) {
+	locked := []string{"assignment-1", "assignment-2"}
+
+	if err := checkBranch("assignment-2", locked); !errors.Is(err, ErrBranchLocked) {
+		t.Errorf("checkBranch(locked) = %v, want ErrBranchLocked", err)
+	}
+	if err := checkBranch("assignment-10", locked); err != nil {
+		t.Errorf("checkBranch(unlocked) = %v, want nil", err)
+	}
+}
+
+func TestParsePushedRefs(t *testing.T) {
+	input := strings.Join([]string{
+		"refs/heads/main 1111 refs/heads/main 2222",
+		"refs/heads/local 3333 refs/heads/feature/x 4444",
+		"refs/tags/v1 5555 refs/tags/v1 6666",
+		"malformed line",
+		"",
+	}, "\n")
+
+	remoteRefs, err := parsePushedRefs(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parsePushedRefs() error = %v", err)
+	}
+
+	want := []string{"refs/heads/main", "refs/heads/feature/x", "refs/tags/v1"}
+	if !slices.Equal(remoteRefs, want) {
+		t.Errorf("parsePushedRefs() = %v, want %v", remoteRefs, want)
+	}
+}
@@ -1,10 +1,13 @@
 package git
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -14,6 +17,18 @@ const (
 	DefaultBranch = "main"
 )
 
+// Errors returned by git operations that callers may want to distinguish
+var (
+	// ErrNotFound is returned when a requested path does not exist in the given ref
+	ErrNotFound = errors.New("path not found in ref")
+
+	// ErrNotFastForward is returned when a fast-forward-only pull is not possible
+	ErrNotFastForward = errors.New("not possible to fast-forward")
+
+	// ErrRemoteUnavailable is returned when the remote cannot be reached or read
+	ErrRemoteUnavailable = errors.New("remote repository unavailable")
+)
+
 // Commander handles git command execution
 type Commander struct {
 	dryRun bool
@@ -185,6 +200,92 @@ func (o *Operations) PushBranch(branchName string) error {
 	)
 }
 
+// Pull fetches and integrates changes from the given remote branch
+// With neither remote nor branch the configured upstream is pulled; with only a branch
+// the remote defaults to DefaultRemote
+func (o *Operations) Pull(remote, branch string) error {
+	args, err := pullArgs(remote, branch, false)
+	if err != nil {
+		return err
+	}
+	return o.pull(args)
+}
+
+// PullFFOnly fetches and fast-forwards to the given remote branch without creating merge commits
+// Returns ErrNotFastForward if local history has diverged and ErrRemoteUnavailable on network failures
+func (o *Operations) PullFFOnly(remote, branch string) error {
+	args, err := pullArgs(remote, branch, true)
+	if err != nil {
+		return err
+	}
+	return o.pull(args)
+}
+
+// pullArgs builds the git pull argument list
+func pullArgs(remote, branch string, ffOnly bool) ([]string, error) {
+	// Positional values starting with "-" would be parsed by git as options
+	for _, value := range []string{remote, branch} {
+		if strings.HasPrefix(value, "-") {
+			return nil, fmt.Errorf("invalid pull argument '%s': must not start with '-'", value)
+		}
+	}
+
+	args := []string{"pull"}
+	if ffOnly {
+		args = append(args, "--ff-only")
+	}
+
+	// Without remote and branch, git pulls the configured upstream
+	if remote == "" && branch == "" {
+		return args, nil
+	}
+
+	if remote == "" {
+		remote = DefaultRemote
+	}
+	args = append(args, remote)
+	if branch != "" {
+		args = append(args, branch)
+	}
+
+	return args, nil
+}
+
+// pull runs git pull and classifies failures
+func (o *Operations) pull(args []string) error {
+	output, err := o.runGitInContext("Pull changes from remote", args...)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if classified := classifyPullError(string(exitErr.Stderr)); classified != nil {
+				return fmt.Errorf("failed to pull: %w: %v", classified, err)
+			}
+		}
+		return fmt.Errorf("failed to pull: %w", err)
+	}
+
+	if len(output) > 0 {
+		fmt.Printf("  Output: %s\n", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// classifyPullError maps git pull stderr to a sentinel error, or nil if unrecognized
+func classifyPullError(stderr string) error {
+	switch {
+	case strings.Contains(stderr, "Not possible to fast-forward"):
+		return ErrNotFastForward
+	case strings.Contains(stderr, "Could not read from remote repository"),
+		// Only the HTTP transport form, local files report "unable to access '<path>'" too
+		strings.Contains(stderr, "unable to access 'http"),
+		strings.Contains(stderr, "Could not resolve host"),
+		strings.Contains(stderr, "does not appear to be a git repository"):
+		return ErrRemoteUnavailable
+	}
+	return nil
+}
+
 // GetLocalBranches returns a map of local branch names
 func (o *Operations) GetLocalBranches() (map[string]bool, error) {
 	if o.commander.dryRun {
@@ -274,6 +375,26 @@ func (o *Operations) DisableSparseCheckout() error {
 	)
 }
 
+// IsSparseCheckoutEnabled reports whether sparse-checkout is enabled for the working tree
+func (o *Operations) IsSparseCheckoutEnabled() (bool, error) {
+	return o.getConfigBool("core.sparseCheckout")
+}
+
+// getConfigBool reads a boolean git config value, returning false if the key is unset
+func (o *Operations) getConfigBool(key string) (bool, error) {
+	output, err := o.runGitInContext("", "config", "--bool", "--get", key)
+	if err != nil {
+		// git config exits with status 1 when the key is not set
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read git config %s: %w", key, err)
+	}
+
+	return strings.TrimSpace(string(output)) == "true", nil
+}
+
 // GetRepositoryRoot uses Git to find the top-level repository directory
 // This is more reliable than os.Getwd() because Git hooks can be called
 // from any subdirectory within the repository
@@ -326,20 +447,19 @@ func (o *Operations) FindGitDir() (string, error) {
 }
 
 // CheckUnmergedEntries checks for merge conflicts in the specified paths
+// Paths are passed to git as literal pathspecs, like in ListTrackedUnder
 func (o *Operations) CheckUnmergedEntries(paths []string) error {
 	if len(paths) == 0 {
 		return nil
 	}
 
-	pathsStr := strings.Join(paths, " ")
-	command := fmt.Sprintf("git ls-files -u -- %s", pathsStr)
-
-	output, err := o.runCommandInContext(command, "Check for unmerged entries")
+	args := append([]string{"--literal-pathspecs", "ls-files", "-u", "-z", "--"}, paths...)
+	output, err := o.runGitInContext("Check for unmerged entries", args...)
 	if err != nil {
 		return fmt.Errorf("failed to check for unmerged entries: %w", err)
 	}
 
-	if strings.TrimSpace(output) != "" {
+	if len(output) > 0 {
 		return fmt.Errorf("conflicts found in protected paths - resolve first")
 	}
 
@@ -361,18 +481,171 @@ func (o *Operations) BuildSnapshotFromHEAD(paths []string, stageDir string) erro
 	return err
 }
 
-// ApplySkipWorktreeFlags applies skip-worktree flags to tracked files in specified paths
-func (o *Operations) ApplySkipWorktreeFlags(paths []string) error {
+// ListTrackedUnder returns the tracked files under the given paths, relative to the repository root
+// Paths are passed to git as literal pathspecs, so names containing spaces or glob characters are safe
+func (o *Operations) ListTrackedUnder(paths []string) ([]string, error) {
 	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	args := append([]string{"--literal-pathspecs", "ls-files", "-z", "--"}, paths...)
+	output, err := o.runGitInContext("List tracked files", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+
+	return splitNUL(output), nil
+}
+
+// RefExists reports whether ref resolves to a commit, e.g. false for HEAD on an unborn branch
+func (o *Operations) RefExists(ref string) (bool, error) {
+	_, err := o.runGitInContext("", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err == nil {
+		return true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to resolve %s: %w", ref, err)
+}
+
+// ListTrackedInRef returns all files tracked in the given ref, relative to the repository root
+func (o *Operations) ListTrackedInRef(ref string) ([]string, error) {
+	output, err := o.runGitInContext("List files in ref", "ls-tree", "-r", "-z", "--name-only", ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s: %w", ref, err)
+	}
+
+	return splitNUL(output), nil
+}
+
+// ListSkipWorktree returns all index entries that carry the skip-worktree flag
+func (o *Operations) ListSkipWorktree() ([]string, error) {
+	output, err := o.runGitInContext("List skip-worktree entries", "ls-files", "-t", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list skip-worktree entries: %w", err)
+	}
+
+	// Each entry is "<tag> <path>", where tag "S" marks skip-worktree
+	var files []string
+	for _, entry := range splitNUL(output) {
+		if file, ok := strings.CutPrefix(entry, "S "); ok {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+// ApplySkipWorktreeFlagsToFiles applies skip-worktree flags to the given tracked files
+// The files are fed to git on standard input, so the list length is not limited by ARG_MAX
+func (o *Operations) ApplySkipWorktreeFlagsToFiles(files []string) error {
+	if len(files) == 0 {
 		return nil
 	}
 
-	pathsStr := strings.Join(paths, " ")
-	command := fmt.Sprintf("git ls-files -z -- %s | xargs -0 -r git update-index --skip-worktree", pathsStr)
-	_, err := o.runCommandInContext(command, "Apply skip-worktree flags")
+	input := []byte(strings.Join(files, "\x00") + "\x00")
+	_, err := o.runGitInContextWithInput("Apply skip-worktree flags", input, "update-index", "--skip-worktree", "-z", "--stdin")
 	return err
 }
 
+// CatFileSize returns the size in bytes of the blob at path in ref without reading its content
+// Returns ErrNotFound if the path does not exist in the ref, and an error if it is not a blob
+func (o *Operations) CatFileSize(ref, path string) (int64, error) {
+	object := ref + ":" + path
+	if strings.ContainsAny(object, "\n") {
+		return 0, fmt.Errorf("invalid object name %q: contains a newline", object)
+	}
+
+	output, err := o.runGitInContextWithInput("Get blob size", []byte(object+"\n"),
+		"cat-file", "--batch-check=%(objecttype) %(objectsize)")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get size of %s: %w", object, err)
+	}
+
+	if o.commander.dryRun {
+		return 0, nil
+	}
+
+	// Output is "<type> <size>" or "<object> missing" for names that do not resolve
+	fields := strings.Fields(strings.TrimSuffix(string(output), "\n"))
+	if len(fields) == 0 || fields[len(fields)-1] == "missing" {
+		// Tell a missing path apart from a ref that does not exist at all
+		if _, err := o.runGitInContext("", "rev-parse", "--verify", "--quiet", ref+"^{tree}"); err != nil {
+			return 0, fmt.Errorf("failed to get size of %s: invalid ref %s", object, ref)
+		}
+		return 0, fmt.Errorf("%s: %w", object, ErrNotFound)
+	}
+
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("failed to get size of %s: unexpected output %q", object, output)
+	}
+	if fields[0] != "blob" {
+		return 0, fmt.Errorf("failed to get size of %s: object is a %s, not a blob", object, fields[0])
+	}
+
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse size of %s: %w", object, err)
+	}
+
+	return size, nil
+}
+
+// splitNUL splits NUL-terminated git output into its entries
+func splitNUL(output []byte) []string {
+	var entries []string
+	for _, entry := range strings.Split(string(output), "\x00") {
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Helper to run git with an argument slice (no shell) in the working directory context
+// Returns the raw, untrimmed standard output so callers can parse NUL-separated output
+func (o *Operations) runGitInContext(description string, args ...string) ([]byte, error) {
+	return o.runGitInContextWithInput(description, nil, args...)
+}
+
+// Helper to run git with an argument slice and optional standard input in the working directory context
+func (o *Operations) runGitInContextWithInput(description string, input []byte, args ...string) ([]byte, error) {
+	command := "git " + strings.Join(args, " ")
+
+	if o.commander.dryRun {
+		if description != "" {
+			fmt.Printf("[DRY RUN] %s: %s\n", description, command)
+		}
+		return nil, nil
+	}
+
+	if description != "" {
+		fmt.Printf("%s: %s\n", description, command)
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = o.workDir
+	// Force untranslated messages, callers classify failures by git's stderr text
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("error running command '%s': %w\nStderr: %s", command, err, string(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("error running command '%s': %w", command, err)
+	}
+
+	return output, nil
+}
+
 // Helper to run commands with working directory context
 func (o *Operations) runCommandInContext(command, description string) (string, error) {
 	if o.workDir != "" {
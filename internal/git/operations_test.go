@@ -0,0 +1,271 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/majikmate/assignment-pull-request/internal/testutil"
+)
+
+func TestCatFileSize(t *testing.T) {
+	dir := testutil.InitRepo(t, map[string]string{
+		"empty.txt":          "",
+		"small.txt":          "hello\n",
+		"nested/dir/big.bin": strings.Repeat("x", 100000),
+		"with space.txt":     "abc",
+	})
+	ops := NewOperationsWithDir(false, dir)
+
+	tests := map[string]int64{
+		"empty.txt":          0,
+		"small.txt":          6,
+		"nested/dir/big.bin": 100000,
+		"with space.txt":     3,
+	}
+	for path, want := range tests {
+		got, err := ops.CatFileSize("HEAD", path)
+		if err != nil {
+			t.Errorf("CatFileSize(%q) error = %v", path, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("CatFileSize(%q) = %d, want %d", path, got, want)
+		}
+	}
+}
+
+func TestCatFileSizeNotFound(t *testing.T) {
+	dir := testutil.InitRepo(t, map[string]string{"tracked.txt": "x"})
+	if err := os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("y"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ops := NewOperationsWithDir(false, dir)
+
+	for _, path := range []string{"missing.txt", "untracked.txt"} {
+		if _, err := ops.CatFileSize("HEAD", path); !errors.Is(err, ErrNotFound) {
+			t.Errorf("CatFileSize(%q) error = %v, want ErrNotFound", path, err)
+		}
+	}
+
+	// An invalid ref is a real error, not a missing path
+	if _, err := ops.CatFileSize("no-such-ref", "tracked.txt"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("CatFileSize(invalid ref) error = %v, want non-ErrNotFound error", err)
+	}
+}
+
+func TestCatFileSizeRejectsNonBlob(t *testing.T) {
+	ops := NewOperationsWithDir(false, testutil.InitRepo(t, map[string]string{"d/file.txt": "x"}))
+
+	for _, path := range []string{"d", ""} {
+		if size, err := ops.CatFileSize("HEAD", path); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("CatFileSize(%q) = %d, %v, want non-blob error", path, size, err)
+		}
+	}
+}
+
+func TestCatFileSizeNotFoundLocalized(t *testing.T) {
+	t.Setenv("LANG", "de_DE.UTF-8")
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+
+	ops := NewOperationsWithDir(false, testutil.InitRepo(t, map[string]string{"tracked.txt": "x"}))
+	if _, err := ops.CatFileSize("HEAD", "missing.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CatFileSize() under localized environment error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListTrackedUnder(t *testing.T) {
+	dir := testutil.InitRepo(t, map[string]string{
+		"a b/c d/f g.txt": "1",
+		"a b/top":         "2",
+		"x/*":             "3",
+		"x/y":             "4",
+		"other":           "5",
+	})
+	if err := os.WriteFile(filepath.Join(dir, "a b", "untracked"), []byte("u"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ops := NewOperationsWithDir(false, dir)
+
+	got, err := ops.ListTrackedUnder([]string{"a b", "x/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "x/*" is a literal pathspec and must not match x/y
+	want := []string{"a b/c d/f g.txt", "a b/top", "x/*"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ListTrackedUnder() = %q, want %q", got, want)
+	}
+}
+
+func TestCheckUnmergedEntries(t *testing.T) {
+	dir := testutil.InitRepo(t, map[string]string{"a b/f.txt": "base", "x/*": "base", "x/y": "base"})
+	commit := []string{"commit", "-q", "-am"}
+	testutil.RunGit(t, dir, "checkout", "-q", "-b", "other")
+	testutil.WriteFiles(t, dir, map[string]string{"a b/f.txt": "other", "x/y": "other"})
+	testutil.RunGit(t, dir, append(commit, "other")...)
+	testutil.RunGit(t, dir, "checkout", "-q", "main")
+	testutil.WriteFiles(t, dir, map[string]string{"a b/f.txt": "main", "x/y": "main"})
+	testutil.RunGit(t, dir, append(commit, "main")...)
+
+	// The merge is expected to stop with conflicts
+	cmd := exec.Command("git", "merge", "-q", "other")
+	cmd.Dir = dir
+	if err := cmd.Run(); err == nil {
+		t.Fatal("merge unexpectedly succeeded")
+	}
+
+	ops := NewOperationsWithDir(false, dir)
+	if err := ops.CheckUnmergedEntries([]string{"a b"}); err == nil {
+		t.Error("CheckUnmergedEntries(conflicted path with space) = nil, want error")
+	}
+	// "x/*" is a literal pathspec and must not match the conflicted x/y
+	if err := ops.CheckUnmergedEntries([]string{"x/*"}); err != nil {
+		t.Errorf("CheckUnmergedEntries(literal x/*) = %v, want nil", err)
+	}
+}
+
+func TestRefExists(t *testing.T) {
+	unborn := NewOperationsWithDir(false, testutil.InitUnbornRepo(t))
+	if exists, err := unborn.RefExists("HEAD"); err != nil || exists {
+		t.Errorf("RefExists(HEAD) on unborn branch = %v, %v, want false, nil", exists, err)
+	}
+
+	ops := NewOperationsWithDir(false, testutil.InitRepo(t, nil))
+	if exists, err := ops.RefExists("HEAD"); err != nil || !exists {
+		t.Errorf("RefExists(HEAD) = %v, %v, want true, nil", exists, err)
+	}
+}
+
+func TestApplySkipWorktreeFlagsToFiles(t *testing.T) {
+	dir := testutil.InitRepo(t, map[string]string{
+		"a b/f g.txt": "1",
+		"keep.txt":    "2",
+	})
+	ops := NewOperationsWithDir(false, dir)
+
+	if err := ops.ApplySkipWorktreeFlagsToFiles([]string{"a b/f g.txt"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ops.ListSkipWorktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a b/f g.txt"}; !slices.Equal(got, want) {
+		t.Errorf("ListSkipWorktree() = %q, want %q", got, want)
+	}
+}
+
+func TestIsSparseCheckoutEnabled(t *testing.T) {
+	dir := testutil.InitRepo(t, map[string]string{"a/x": "1"})
+	ops := NewOperationsWithDir(false, dir)
+
+	if enabled, err := ops.IsSparseCheckoutEnabled(); err != nil || enabled {
+		t.Errorf("IsSparseCheckoutEnabled() = %v, %v, want false", enabled, err)
+	}
+
+	testutil.RunGit(t, dir, "sparse-checkout", "set", "--cone", "a")
+	if enabled, err := ops.IsSparseCheckoutEnabled(); err != nil || !enabled {
+		t.Errorf("IsSparseCheckoutEnabled() = %v, %v, want true", enabled, err)
+	}
+}
+
+func TestPullArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		remote string
+		branch string
+		ffOnly bool
+		want   []string
+	}{
+		{"upstream", "", "", false, []string{"pull"}},
+		{"upstream ff-only", "", "", true, []string{"pull", "--ff-only"}},
+		{"remote only", "upstream", "", true, []string{"pull", "--ff-only", "upstream"}},
+		{"branch defaults remote", "", "main", true, []string{"pull", "--ff-only", DefaultRemote, "main"}},
+		{"remote and branch", "instructor", "assignment-1", false, []string{"pull", "instructor", "assignment-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pullArgs(tt.remote, tt.branch, tt.ffOnly)
+			if err != nil {
+				t.Fatalf("pullArgs() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pullArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullArgsRejectsOptions(t *testing.T) {
+	for _, args := range [][2]string{
+		{"--upload-pack=touch /tmp/pwned", "main"},
+		{"origin", "-f"},
+	} {
+		if got, err := pullArgs(args[0], args[1], true); err == nil {
+			t.Errorf("pullArgs(%q, %q) = %q, want error", args[0], args[1], got)
+		}
+	}
+}
+
+func TestClassifyPullError(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   error
+	}{
+		{"fatal: Not possible to fast-forward, aborting.\n", ErrNotFastForward},
+		{"fatal: 'nowhere' does not appear to be a git repository\nfatal: Could not read from remote repository.\n", ErrRemoteUnavailable},
+		{"fatal: unable to access 'https://example.invalid/x/': Could not resolve host: example.invalid\n", ErrRemoteUnavailable},
+		{"fatal: unable to access 'http://example.invalid/x/': Failed to connect to example.invalid port 80\n", ErrRemoteUnavailable},
+		{"fatal: unable to access '/home/u/.config/git/config': Permission denied\n", nil},
+		{"error: Your local changes to the following files would be overwritten by merge:\n", nil},
+	}
+
+	for _, tt := range tests {
+		if got := classifyPullError(tt.stderr); got != tt.want {
+			t.Errorf("classifyPullError(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestPullFFOnlyDiverged(t *testing.T) {
+	upstream := testutil.InitRepo(t, map[string]string{"a.txt": "1"})
+	clone := filepath.Join(t.TempDir(), "clone")
+	testutil.RunGit(t, upstream, "clone", "-q", upstream, clone)
+
+	commit := []string{"-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-q", "--allow-empty", "-m"}
+	testutil.RunGit(t, upstream, append(commit, "upstream")...)
+	testutil.RunGit(t, clone, append(commit, "local")...)
+
+	ops := NewOperationsWithDir(false, clone)
+	if err := ops.PullFFOnly("", "main"); !errors.Is(err, ErrNotFastForward) {
+		t.Errorf("PullFFOnly(diverged) error = %v, want ErrNotFastForward", err)
+	}
+	if err := ops.PullFFOnly(filepath.Join(t.TempDir(), "missing"), "main"); !errors.Is(err, ErrRemoteUnavailable) {
+		t.Errorf("PullFFOnly(missing remote) error = %v, want ErrRemoteUnavailable", err)
+	}
+}
+
+func TestPullFFOnlyFastForward(t *testing.T) {
+	upstream := testutil.InitRepo(t, map[string]string{"a.txt": "1"})
+	clone := filepath.Join(t.TempDir(), "clone")
+	testutil.RunGit(t, upstream, "clone", "-q", upstream, clone)
+	testutil.RunGit(t, upstream, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "-q", "--allow-empty", "-m", "upstream")
+
+	if err := NewOperationsWithDir(false, clone).PullFFOnly("", ""); err != nil {
+		t.Errorf("PullFFOnly(upstream) error = %v", err)
+	}
+}
+
+func TestPullDryRun(t *testing.T) {
+	if err := NewOperationsWithDir(true, t.TempDir()).PullFFOnly("origin", "main"); err != nil {
+		t.Errorf("PullFFOnly(dry run) error = %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -36,6 +37,36 @@ func newInfo(entries []PathEntry) *Info {
 	}
 }
 
+// WithEntries returns a new Info containing the existing entries plus the given ones,
+// deduplicated by absolute path and sorted for consistent output
+func (i *Info) WithEntries(entries ...PathEntry) *Info {
+	seen := make(map[string]bool, len(i.entries)+len(entries))
+	merged := make([]PathEntry, 0, len(i.entries)+len(entries))
+	for _, entry := range append(append([]PathEntry{}, i.entries...), entries...) {
+		if !seen[entry.Path] {
+			seen[entry.Path] = true
+			merged = append(merged, entry)
+		}
+	}
+
+	sort.Slice(merged, func(a, b int) bool {
+		return merged[a].Path < merged[b].Path
+	})
+
+	return newInfo(merged)
+}
+
+// Filter returns a new Info containing only the entries for which keep returns true
+func (i *Info) Filter(keep func(PathEntry) bool) *Info {
+	var filtered []PathEntry
+	for _, entry := range i.entries {
+		if keep(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return newInfo(filtered)
+}
+
 // Paths returns all path entry objects
 func (i *Info) Paths() []PathEntry {
 	return i.entries
@@ -185,41 +216,70 @@ type FindOptions struct {
 
 // FindWithOptions discovers all paths matching the processor's regex patterns with custom options
 func (p *Processor) FindWithOptions(opts FindOptions) (*Info, error) {
-	// Set defaults
-	if !opts.IncludeFiles && !opts.IncludeDirs {
-		opts.IncludeFiles = true
-		opts.IncludeDirs = true
-	}
-	if opts.LogPrefix == "" {
-		opts.LogPrefix = "🔍"
-	}
-	if opts.LogDescription == "" {
-		opts.LogDescription = "paths"
+	results, err := FindAll(p.root, Query{Patterns: p.patterns, Options: opts})
+	if err != nil {
+		return nil, err
 	}
+	return results[0], nil
+}
 
-	fmt.Printf("%s Searching for %s...\n", opts.LogPrefix, opts.LogDescription)
-	var matchedPaths []struct {
-		absolutePath string
-		relativePath string
-	}
+// Query pairs a set of regex patterns with the options used to match them
+type Query struct {
+	Patterns *regex.Processor
+	Options  FindOptions
+}
 
+// queryState tracks the compiled patterns and matches of a single query during a walk
+type queryState struct {
+	opts         FindOptions
+	compiled     []*regexp.Regexp
+	entries      []PathEntry
+	checkedPaths int
+}
+
+// FindAll discovers the paths matching each query in a single walk of the directory tree
+// Every path is matched against all queries, so a path may appear in more than one result.
+// Results are returned in the same order as the queries and are independent of each other.
+// Queries without patterns yield an empty result.
+func FindAll(root string, queries ...Query) ([]*Info, error) {
 	// Determine the root directory to walk
-	rootDir := p.root
+	rootDir := root
 	if rootDir == "" {
 		rootDir = "."
 	}
 
-	// Get compiled patterns
-	compiledPatterns, err := p.patterns.Compiled()
-	if err != nil {
-		return nil, fmt.Errorf("failed to compile path patterns: %w", err)
-	}
+	states := make([]*queryState, len(queries))
+	for idx, query := range queries {
+		opts := query.Options
 
-	checkedPaths := 0
-	matchedCount := 0
+		// Set defaults
+		if !opts.IncludeFiles && !opts.IncludeDirs {
+			opts.IncludeFiles = true
+			opts.IncludeDirs = true
+		}
+		if opts.LogPrefix == "" {
+			opts.LogPrefix = "🔍"
+		}
+		if opts.LogDescription == "" {
+			opts.LogDescription = "paths"
+		}
 
-	// Walk the entire directory tree and check each path against patterns
-	err = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		// Get compiled patterns
+		var compiledPatterns []*regexp.Regexp
+		if query.Patterns != nil {
+			var err error
+			compiledPatterns, err = query.Patterns.Compiled()
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile path patterns: %w", err)
+			}
+		}
+
+		fmt.Printf("%s Searching for %s...\n", opts.LogPrefix, opts.LogDescription)
+		states[idx] = &queryState{opts: opts, compiled: compiledPatterns}
+	}
+
+	// Walk the entire directory tree once and check each path against all queries
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -240,16 +300,6 @@ func (p *Processor) FindWithOptions(opts FindOptions) (*Info, error) {
 			return nil
 		}
 
-		// Filter by file type if specified
-		if info.IsDir() && !opts.IncludeDirs {
-			return nil
-		}
-		if !info.IsDir() && !opts.IncludeFiles {
-			return nil
-		}
-
-		checkedPaths++
-
 		// Convert absolute path to relative path from root
 		relativePath, err := filepath.Rel(rootDir, path)
 		if err != nil {
@@ -259,18 +309,26 @@ func (p *Processor) FindWithOptions(opts FindOptions) (*Info, error) {
 		// Use the relative path for pattern matching
 		relativeNormalizedPath := filepath.ToSlash(relativePath)
 
-		// Check if this path matches any of the patterns
-		for _, pattern := range compiledPatterns {
-			if pattern.MatchString(relativeNormalizedPath) {
-				matchedPaths = append(matchedPaths, struct {
-					absolutePath string
-					relativePath string
-				}{
-					absolutePath: path,
-					relativePath: relativePath,
-				})
-				matchedCount++
-				break // Don't check other patterns for this path
+		for _, state := range states {
+			// Filter by file type if specified
+			if info.IsDir() && !state.opts.IncludeDirs {
+				continue
+			}
+			if !info.IsDir() && !state.opts.IncludeFiles {
+				continue
+			}
+
+			state.checkedPaths++
+
+			// Check if this path matches any of the query's patterns
+			for _, pattern := range state.compiled {
+				if pattern.MatchString(relativeNormalizedPath) {
+					state.entries = append(state.entries, PathEntry{
+						Path:         path,
+						RelativePath: relativePath,
+					})
+					break // Don't check other patterns for this path
+				}
 			}
 		}
 
@@ -278,26 +336,26 @@ func (p *Processor) FindWithOptions(opts FindOptions) (*Info, error) {
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("error finding %s: %w", opts.LogDescription, err)
+		descriptions := make([]string, len(states))
+		for idx, state := range states {
+			descriptions[idx] = state.opts.LogDescription
+		}
+		return nil, fmt.Errorf("error finding %s: %w", strings.Join(descriptions, ", "), err)
 	}
 
-	// Sort paths by absolute path for consistent output
-	sort.Slice(matchedPaths, func(i, j int) bool {
-		return matchedPaths[i].absolutePath < matchedPaths[j].absolutePath
-	})
+	results := make([]*Info, len(states))
+	for idx, state := range states {
+		// Sort paths by absolute path for consistent output
+		sort.Slice(state.entries, func(i, j int) bool {
+			return state.entries[i].Path < state.entries[j].Path
+		})
 
-	fmt.Printf("%s Found %d %s (checked %d paths total)\n", opts.LogPrefix, matchedCount, opts.LogDescription, checkedPaths)
+		fmt.Printf("%s Found %d %s (checked %d paths total)\n", state.opts.LogPrefix, len(state.entries), state.opts.LogDescription, state.checkedPaths)
 
-	// Convert paths to PathEntry structs and return Info
-	var pathEntries []PathEntry
-	for _, pathPair := range matchedPaths {
-		pathEntries = append(pathEntries, PathEntry{
-			Path:         pathPair.absolutePath,
-			RelativePath: pathPair.relativePath,
-		})
+		results[idx] = newInfo(state.entries)
 	}
 
-	return newInfo(pathEntries), nil
+	return results, nil
 }
 
 // GetRegexStrings returns the regex patterns as strings
@@ -0,0 +1,178 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/majikmate/assignment-pull-request/internal/regex"
+)
+
+// createTree creates the given slash-separated files below a temporary root
+func createTree(t testing.TB, files ...string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for _, name := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestFindAllResultsAreIndependent(t *testing.T) {
+	root := createTree(t,
+		"assignments/assignment-1/README.md",
+		"assignments/assignment-2/README.md",
+		"tutorials/intro.md",
+	)
+
+	dirsOnly := FindOptions{IncludeDirs: true}
+	all := FindOptions{IncludeFiles: true, IncludeDirs: true}
+
+	results, err := FindAll(root,
+		Query{Patterns: regex.NewWithPatterns([]string{`^assignments/assignment-\d+$`}), Options: dirsOnly},
+		Query{Patterns: regex.NewWithPatterns([]string{`^assignments/assignment-1`, `^tutorials$`}), Options: all},
+		Query{Patterns: regex.New(), Options: all},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// assignments/assignment-1 matches both queries and must appear in both results
+	wantFirst := []string{"assignments/assignment-1", "assignments/assignment-2"}
+	wantSecond := []string{"assignments/assignment-1", "assignments/assignment-1/README.md", "tutorials"}
+
+	if got := results[0].RelativePaths(); !slices.Equal(got, wantFirst) {
+		t.Errorf("first result = %v, want %v", got, wantFirst)
+	}
+	if got := results[1].RelativePaths(); !slices.Equal(got, wantSecond) {
+		t.Errorf("second result = %v, want %v", got, wantSecond)
+	}
+	if !results[2].Empty() {
+		t.Errorf("query without patterns = %v, want empty", results[2].RelativePaths())
+	}
+}
+
+func TestFindAllMatchesSeparateFinds(t *testing.T) {
+	root := createTree(t,
+		"a/one/x.txt",
+		"a/two/y.txt",
+		".github/workflows/ci.yml",
+	)
+
+	patterns := []*regex.Processor{
+		regex.NewWithPatterns([]string{`^a/[^/]+$`}),
+		regex.NewWithPatterns([]string{`^\.github$`, `\.txt$`}),
+	}
+	options := []FindOptions{
+		{IncludeDirs: true},
+		{IncludeFiles: true, IncludeDirs: true},
+	}
+
+	results, err := FindAll(root,
+		Query{Patterns: patterns[0], Options: options[0]},
+		Query{Patterns: patterns[1], Options: options[1]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for idx := range patterns {
+		processor, err := NewProcessor(root, patterns[idx])
+		if err != nil {
+			t.Fatal(err)
+		}
+		separate, err := processor.FindWithOptions(options[idx])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(results[idx].RelativePaths(), separate.RelativePaths()) {
+			t.Errorf("query %d: FindAll = %v, FindWithOptions = %v", idx, results[idx].RelativePaths(), separate.RelativePaths())
+		}
+	}
+}
+
+// silenceStdout discards the progress output of path searches for the duration of a benchmark
+func silenceStdout(tb testing.TB) {
+	tb.Helper()
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = devNull
+	tb.Cleanup(func() {
+		os.Stdout = stdout
+		devNull.Close()
+	})
+}
+
+// benchmarkQueries builds a tree of 50 assignments with 40 files each and the assignment and
+// protected queries run against it
+func benchmarkQueries(b *testing.B) (string, []Query) {
+	b.Helper()
+
+	var files []string
+	for a := 0; a < 50; a++ {
+		for f := 0; f < 40; f++ {
+			files = append(files, fmt.Sprintf("assignments/assignment-%d/src/file-%d.txt", a, f))
+		}
+	}
+	files = append(files, "tutorials/intro.md")
+
+	root := createTree(b, files...)
+	silenceStdout(b)
+
+	return root, []Query{
+		{
+			Patterns: regex.NewWithPatterns([]string{`^assignments/(assignment-\d+)$`}),
+			Options:  FindOptions{IncludeDirs: true, LogPrefix: "📁", LogDescription: "assignment folders"},
+		},
+		{
+			Patterns: regex.NewWithPatterns([]string{`^tutorials$`, `^assignments/assignment-1/src$`}),
+			Options:  FindOptions{IncludeFiles: true, IncludeDirs: true, LogPrefix: "🔒", LogDescription: "protected paths"},
+		},
+	}
+}
+
+func BenchmarkFindAllSingleWalk(b *testing.B) {
+	root, queries := benchmarkQueries(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := FindAll(root, queries...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindAllSeparateWalks(b *testing.B) {
+	root, queries := benchmarkQueries(b)
+
+	processors := make([]*Processor, len(queries))
+	for idx, query := range queries {
+		processor, err := NewProcessor(root, query.Patterns)
+		if err != nil {
+			b.Fatal(err)
+		}
+		processors[idx] = processor
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for idx, processor := range processors {
+			if _, err := processor.FindWithOptions(queries[idx].Options); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package protect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/majikmate/assignment-pull-request/internal/paths"
+	"github.com/majikmate/assignment-pull-request/internal/regex"
+)
+
+// listHEADFiles returns the files committed in HEAD, or nil if there is nothing to compare
+// against: no protection patterns are configured or the branch has no commits yet
+func (p *Processor) listHEADFiles(protectedFoldersPattern *regex.Processor) ([]string, error) {
+	if len(protectedFoldersPattern.Patterns()) == 0 {
+		return nil, nil
+	}
+
+	exists, err := p.gitOps.RefExists("HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	return p.gitOps.ListTrackedInRef("HEAD")
+}
+
+// addMissingProtectedPaths extends the protected set with paths tracked in HEAD that match the
+// protection patterns but are missing from the working tree
+//
+// findProtectedPaths walks the filesystem, so a protected file or directory deleted by the
+// student (e.g. with rm, which skip-worktree does not prevent) would otherwise never be restored.
+// Adding it here makes the HEAD snapshot include it, so the sync recreates it and skip-worktree
+// is re-applied. Missing paths whose files are all sparse-excluded are expected to be absent
+// and are not added.
+func (p *Processor) addMissingProtectedPaths(protectedFoldersPattern *regex.Processor, protectedPathsInfo *paths.Info, headFiles []string, sparse *sparseCheckout) (*paths.Info, error) {
+	if len(headFiles) == 0 {
+		return protectedPathsInfo, nil
+	}
+
+	pathsProcessor, err := paths.NewProcessor(p.repositoryRoot, protectedFoldersPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create paths processor: %w", err)
+	}
+
+	// Map each missing protected path to whether it has a file to restore
+	restorable := make(map[string]bool)
+	var candidates []string
+	present := make(map[string]bool)
+	for _, file := range headFiles {
+		protectedPath, err := highestProtectedPath(pathsProcessor, file)
+		if err != nil {
+			return nil, err
+		}
+		if protectedPath == "" || present[protectedPath] {
+			continue
+		}
+
+		if _, seen := restorable[protectedPath]; !seen {
+			absolutePath := filepath.Join(p.repositoryRoot, filepath.FromSlash(protectedPath))
+			if _, err := os.Lstat(absolutePath); err == nil {
+				present[protectedPath] = true // Present paths are already covered by the filesystem walk
+				continue
+			}
+			restorable[protectedPath] = false
+			candidates = append(candidates, protectedPath)
+		}
+
+		if !sparse.isExcluded(file) {
+			restorable[protectedPath] = true
+		}
+	}
+
+	var missing []paths.PathEntry
+	for _, protectedPath := range candidates {
+		if !restorable[protectedPath] {
+			continue // Entirely sparse-excluded, absence is expected
+		}
+		missing = append(missing, paths.PathEntry{
+			Path:         filepath.Join(p.repositoryRoot, filepath.FromSlash(protectedPath)),
+			RelativePath: filepath.FromSlash(protectedPath),
+		})
+	}
+
+	if len(missing) == 0 {
+		return protectedPathsInfo, nil
+	}
+
+	fmt.Printf("  🩹 Restoring %d protected path(s) missing from working tree:\n", len(missing))
+	for _, entry := range missing {
+		fmt.Printf("    - %s\n", entry.RelativePath)
+	}
+
+	return protectedPathsInfo.WithEntries(missing...), nil
+}
+
+// highestProtectedPath returns the shortest prefix of a tracked file (slash-separated) that
+// matches the protection patterns, or "" if none does
+//
+// Hidden files are only considered through a matching parent directory, mirroring the
+// filesystem walk which skips hidden files.
+func highestProtectedPath(pathsProcessor *paths.Processor, file string) (string, error) {
+	components := strings.Split(file, "/")
+	for idx := range components {
+		isFile := idx == len(components)-1
+		if isFile && strings.HasPrefix(components[idx], ".") {
+			return "", nil
+		}
+
+		prefix := strings.Join(components[:idx+1], "/")
+		matched, err := pathsProcessor.IsPathMatched(prefix)
+		if err != nil {
+			return "", err
+		}
+		if matched {
+			return prefix, nil
+		}
+	}
+
+	return "", nil
+}
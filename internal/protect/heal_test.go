@@ -0,0 +1,163 @@
+package protect
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/majikmate/assignment-pull-request/internal/paths"
+	"github.com/majikmate/assignment-pull-request/internal/regex"
+	"github.com/majikmate/assignment-pull-request/internal/testutil"
+)
+
+// newTestProcessor returns a processor whose sync copies the snapshot into the working tree
+// instead of running the privileged githook-rsync
+func newTestProcessor(t *testing.T, dir string) *Processor {
+	t.Helper()
+
+	processor := New(dir)
+	processor.syncStage = func(stageDir, repositoryRoot string) error {
+		if output, err := exec.Command("cp", "-R", stageDir+"/.", repositoryRoot).CombinedOutput(); err != nil {
+			t.Fatalf("copy stage failed: %v\n%s", err, output)
+		}
+		return nil
+	}
+	return processor
+}
+
+func assertFileContent(t *testing.T, dir, name string) {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		t.Errorf("%s not restored: %v", name, err)
+		return
+	}
+	if string(content) != name {
+		t.Errorf("%s content = %q, want %q", name, content, name)
+	}
+}
+
+func TestProtectPathsRestoresDeletedFiles(t *testing.T) {
+	dir := testutil.InitRepo(t, testutil.Files("prot/a/x", "prot/top", "gone/z", "keep.md", "del.md", "free.txt"))
+	processor := newTestProcessor(t, dir)
+	pattern := regex.NewWithPatterns([]string{"^prot$", "^gone$", `\.md$`})
+
+	// First run protects and flags the files, then the student deletes some of them
+	if err := processor.ProtectPaths(pattern); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"prot/a/x", "del.md", "gone"} {
+		if err := os.RemoveAll(filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := processor.ProtectPaths(pattern); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"prot/a/x", "del.md", "gone/z"} {
+		assertFileContent(t, dir, name)
+	}
+	if got := testutil.RunGit(t, dir, "ls-files", "-t", "prot", "gone", "del.md"); got != "S del.md\nS gone/z\nS prot/a/x\nS prot/top\n" {
+		t.Errorf("ls-files -t = %q, want skip-worktree re-applied", got)
+	}
+}
+
+func TestProtectPathsRestoresDeletedFilesInSparseCheckout(t *testing.T) {
+	for _, mode := range []string{"--cone", "--no-cone"} {
+		t.Run(mode, func(t *testing.T) {
+			dir := testutil.InitRepo(t, testutil.Files("prot/a/x", "prot/b/y", "prot/top", "other/z"))
+			if mode == "--cone" {
+				testutil.RunGit(t, dir, "sparse-checkout", "set", "--cone", "prot/a")
+			} else {
+				testutil.RunGit(t, dir, "sparse-checkout", "set", "--no-cone", "/prot/a/", "/prot/top")
+			}
+			processor := newTestProcessor(t, dir)
+			pattern := regex.NewWithPatterns([]string{"^prot$", "^other$"})
+
+			if err := processor.ProtectPaths(pattern); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Remove(filepath.Join(dir, "prot", "a", "x")); err != nil {
+				t.Fatal(err)
+			}
+			if err := processor.ProtectPaths(pattern); err != nil {
+				t.Fatal(err)
+			}
+
+			assertFileContent(t, dir, "prot/a/x")
+			for _, path := range []string{"prot/b", "other"} {
+				if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path))); !os.IsNotExist(err) {
+					t.Errorf("sparse-excluded %s materialized: %v", path, err)
+				}
+			}
+		})
+	}
+}
+
+func TestProtectPathsUnbornBranch(t *testing.T) {
+	processor := newTestProcessor(t, testutil.InitUnbornRepo(t))
+
+	if err := processor.ProtectPaths(regex.NewWithPatterns([]string{"^prot$"})); err != nil {
+		t.Errorf("ProtectPaths() on unborn branch = %v, want nil", err)
+	}
+}
+
+func TestAddMissingProtectedPathsSkipsHiddenFiles(t *testing.T) {
+	dir := testutil.InitRepo(t, testutil.Files("sub/.hidden.md"))
+	processor := New(dir)
+	pattern := regex.NewWithPatterns([]string{`\.md$`})
+
+	if err := os.Remove(filepath.Join(dir, "sub", ".hidden.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := processor.addMissingProtectedPaths(pattern, emptyInfo(t, processor, pattern), []string{"sub/.hidden.md"}, &sparseCheckout{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Empty() {
+		t.Errorf("hidden file added: %v", info.RelativePaths())
+	}
+}
+
+func TestAddMissingProtectedPathsSkipsSparseExcluded(t *testing.T) {
+	dir := testutil.InitRepo(t, testutil.Files("prot/a/x", "other/z"))
+	testutil.RunGit(t, dir, "sparse-checkout", "set", "--cone", "prot")
+	processor := New(dir)
+	pattern := regex.NewWithPatterns([]string{"^other$"})
+
+	sparse, err := processor.readSparseCheckout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	headFiles, err := processor.listHEADFiles(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := processor.addMissingProtectedPaths(pattern, emptyInfo(t, processor, pattern), headFiles, sparse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Empty() {
+		t.Errorf("sparse-excluded directory reported for restore: %v", info.RelativePaths())
+	}
+}
+
+// emptyInfo returns the walk result for a pattern that matches nothing on disk
+func emptyInfo(t *testing.T, p *Processor, pattern *regex.Processor) *paths.Info {
+	t.Helper()
+
+	info, err := p.findProtectedPaths(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Empty() {
+		t.Fatalf("expected no paths on disk, got %v", info.RelativePaths())
+	}
+	return info
+}
@@ -3,6 +3,7 @@ package protect
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/majikmate/assignment-pull-request/internal/git"
 	"github.com/majikmate/assignment-pull-request/internal/paths"
@@ -10,30 +11,58 @@ import (
 	"github.com/majikmate/assignment-pull-request/internal/regex"
 )
 
+// Options controls optional behavior of the protect flow
+type Options struct {
+	// Umask, if set, is applied for the duration of the protect flow and restored afterward.
+	// It governs the files created by the flow itself: the staging directory and the HEAD
+	// snapshot extracted into it. Final working tree modes are set explicitly by the sync.
+	// (default: nil, leave the inherited umask untouched)
+	Umask *int
+
+	// TrackedOnly limits protection to matched paths that are, or contain, files committed in
+	// HEAD, so untracked or newly staged student files matching a protected pattern are left alone
+	// (default: false, protect every path found by the filesystem walk)
+	TrackedOnly bool
+}
+
 // Processor handles path protection operations
 type Processor struct {
 	repositoryRoot string
 	gitOps         *git.Operations
+	opts           Options
+	syncStage      func(stageDir, repositoryRoot string) error // Mirrors the snapshot into the working tree
 }
 
 // New creates a new protect processor
 func New(repositoryRoot string) *Processor {
+	return NewWithOptions(repositoryRoot, Options{})
+}
+
+// NewWithOptions creates a new protect processor with custom options
+func NewWithOptions(repositoryRoot string, opts Options) *Processor {
 	return &Processor{
 		repositoryRoot: repositoryRoot,
 		gitOps:         git.NewOperationsWithDir(false, repositoryRoot), // Use repository root as working directory
+		opts:           opts,
+		syncStage:      syncWithPermissions,
 	}
 }
 
 // ProtectPaths implements the protect-sync logic in Go:
 // 1. Acquire exclusive lock to prevent concurrent operations
-// 2. Find protected paths using regex patterns
+// 2. Find protected paths using regex patterns, including ones deleted from the working tree
 // 3. Check for unmerged entries under protected paths
-// 4. Extract files from HEAD for protected paths
-// 5. Mirror to working tree with majikmate ownership and permissions
-// 6. Apply skip-worktree flags
+// 4. Skip tracked protected files excluded by sparse-checkout
+// 5. Extract files from HEAD for protected paths
+// 6. Mirror to working tree with majikmate ownership and permissions
+// 7. Apply skip-worktree flags
 func (p *Processor) ProtectPaths(protectedFoldersPattern *regex.Processor) error {
 	fmt.Printf("🔒 Starting path protection (protect-sync logic)...\n")
 
+	// Apply the configured umask for all files created during the flow
+	restoreUmask := applyUmask(p.opts.Umask)
+	defer restoreUmask()
+
 	// Acquire exclusive lock to prevent concurrent protect operations
 	lock, err := acquireLock(p.repositoryRoot)
 	if err != nil {
@@ -45,12 +74,33 @@ func (p *Processor) ProtectPaths(protectedFoldersPattern *regex.Processor) error
 		}
 	}()
 
+	// Determine sparse-excluded files before the working tree is touched
+	sparse, err := p.readSparseCheckout()
+	if err != nil {
+		return err
+	}
+
 	// Find protected paths using patterns
 	protectedPathsInfo, err := p.findProtectedPaths(protectedFoldersPattern)
 	if err != nil {
 		return err
 	}
 
+	headFiles, err := p.listHEADFiles(protectedFoldersPattern)
+	if err != nil {
+		return err
+	}
+
+	// Include protected paths deleted from the working tree so they are restored from HEAD
+	protectedPathsInfo, err = p.addMissingProtectedPaths(protectedFoldersPattern, protectedPathsInfo, headFiles, sparse)
+	if err != nil {
+		return err
+	}
+
+	if p.opts.TrackedOnly {
+		protectedPathsInfo = filterTrackedPaths(protectedPathsInfo, headFiles)
+	}
+
 	if protectedPathsInfo.Empty() {
 		fmt.Println("No paths match protected patterns")
 		return nil
@@ -63,17 +113,26 @@ func (p *Processor) ProtectPaths(protectedFoldersPattern *regex.Processor) error
 		return err
 	}
 
+	trackedFiles, sparseExcluded, err := p.reconcileSparseCheckout(protectedPathsInfo, sparse)
+	if err != nil {
+		return err
+	}
+
 	stageDir, err := p.buildSnapshotFromHEAD(protectedPathsInfo)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(stageDir)
 
+	if err := pruneSparseExcluded(stageDir, sparseExcluded); err != nil {
+		return err
+	}
+
 	if err := p.updatePermissionsInWorkingTree(stageDir, protectedPathsInfo); err != nil {
 		return err
 	}
 
-	if err := p.applySkipWorktreeFlags(protectedPathsInfo); err != nil {
+	if err := p.applySkipWorktreeFlags(trackedFiles, sparse); err != nil {
 		return err
 	}
 
@@ -81,6 +140,16 @@ func (p *Processor) ProtectPaths(protectedFoldersPattern *regex.Processor) error
 	return nil
 }
 
+// SearchOptions returns the path search options used to discover protected paths
+func SearchOptions() paths.FindOptions {
+	return paths.FindOptions{
+		IncludeFiles:   true,
+		IncludeDirs:    true,
+		LogPrefix:      "🔒",
+		LogDescription: "protected paths",
+	}
+}
+
 // findProtectedPaths discovers paths matching the protection patterns and returns Info for flexible usage
 func (p *Processor) findProtectedPaths(protectedFoldersPattern *regex.Processor) (*paths.Info, error) {
 	pathsProcessor, err := paths.NewProcessor(p.repositoryRoot, protectedFoldersPattern)
@@ -88,12 +157,7 @@ func (p *Processor) findProtectedPaths(protectedFoldersPattern *regex.Processor)
 		return nil, fmt.Errorf("failed to create paths processor: %w", err)
 	}
 
-	info, err := pathsProcessor.FindWithOptions(paths.FindOptions{
-		IncludeFiles:   true,
-		IncludeDirs:    true,
-		LogPrefix:      "🔒",
-		LogDescription: "protected paths",
-	})
+	info, err := pathsProcessor.FindWithOptions(SearchOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to find protected paths: %w", err)
 	}
@@ -101,6 +165,41 @@ func (p *Processor) findProtectedPaths(protectedFoldersPattern *regex.Processor)
 	return info, nil
 }
 
+// filterTrackedPaths drops matched paths that neither are nor contain a file committed in HEAD.
+// HEAD rather than the index is the reference, since the snapshot is built from HEAD and a
+// student could otherwise opt a path in or out by staging or removing it
+func filterTrackedPaths(protectedPathsInfo *paths.Info, headFiles []string) *paths.Info {
+	if protectedPathsInfo.Empty() {
+		return protectedPathsInfo
+	}
+
+	// Mark every tracked file and each of its parent directories
+	tracked := make(map[string]bool)
+	for _, file := range headFiles {
+		for path := file; path != "." && !tracked[path]; path = filepath.ToSlash(filepath.Dir(path)) {
+			tracked[path] = true
+		}
+	}
+
+	var skipped []string
+	filtered := protectedPathsInfo.Filter(func(entry paths.PathEntry) bool {
+		if tracked[filepath.ToSlash(entry.RelativePath)] {
+			return true
+		}
+		skipped = append(skipped, entry.RelativePath)
+		return false
+	})
+
+	if len(skipped) > 0 {
+		fmt.Printf("  Skipping %d path(s) not committed in HEAD matching protected patterns:\n", len(skipped))
+		for _, path := range skipped {
+			fmt.Printf("    - %s\n", path)
+		}
+	}
+
+	return filtered
+}
+
 // checkUnmergedEntries verifies no merge conflicts exist in protected paths
 func (p *Processor) checkUnmergedEntries(protectedPathsInfo *paths.Info) error {
 	if protectedPathsInfo.Empty() {
@@ -109,8 +208,7 @@ func (p *Processor) checkUnmergedEntries(protectedPathsInfo *paths.Info) error {
 
 	fmt.Printf("  Checking for merge conflicts in protected paths...\n")
 
-	quotedPaths := protectedPathsInfo.QuotedRelativePaths()
-	return p.gitOps.CheckUnmergedEntries(quotedPaths)
+	return p.gitOps.CheckUnmergedEntries(protectedPathsInfo.RelativePaths())
 }
 
 // buildSnapshotFromHEAD creates a staging directory with files from HEAD
@@ -165,14 +263,7 @@ func (p *Processor) updatePermissionsInWorkingTree(stageDir string, protectedPat
 		return nil
 	}
 
-	// Create PermissionsProcessor instance
-	permissionsProcessor, err := permissions.NewProcessor()
-	if err != nil {
-		return fmt.Errorf("failed to create permissions processor: %w", err)
-	}
-
-	// Execute githook-rsync with sudo for ownership operations
-	if err := permissionsProcessor.ExecuteUpdatePermissions(stageDir, p.repositoryRoot); err != nil {
+	if err := p.syncStage(stageDir, p.repositoryRoot); err != nil {
 		return err
 	}
 
@@ -180,14 +271,31 @@ func (p *Processor) updatePermissionsInWorkingTree(stageDir string, protectedPat
 	return nil
 }
 
-// applySkipWorktreeFlags sets skip-worktree flags on all tracked files in protected paths
-func (p *Processor) applySkipWorktreeFlags(protectedPathsInfo *paths.Info) error {
-	if protectedPathsInfo.Empty() {
+// syncWithPermissions executes githook-rsync with sudo to mirror the snapshot with majikmate ownership
+func syncWithPermissions(stageDir, repositoryRoot string) error {
+	permissionsProcessor, err := permissions.NewProcessor()
+	if err != nil {
+		return fmt.Errorf("failed to create permissions processor: %w", err)
+	}
+
+	return permissionsProcessor.ExecuteUpdatePermissions(stageDir, repositoryRoot)
+}
+
+// applySkipWorktreeFlags sets skip-worktree flags on the tracked protected files present in the working tree
+//
+// With sparse-checkout enabled, git clears skip-worktree from files present in the working tree
+// and uses the flag to mark sparse-excluded files, so the flags are left to git in that mode.
+func (p *Processor) applySkipWorktreeFlags(trackedFiles []string, sparse *sparseCheckout) error {
+	if len(trackedFiles) == 0 {
+		return nil
+	}
+
+	if sparse.enabled {
+		fmt.Printf("  Skipping skip-worktree flags (managed by sparse-checkout)...\n")
 		return nil
 	}
 
 	fmt.Printf("  Applying skip-worktree flags...\n")
 
-	quotedPaths := protectedPathsInfo.QuotedRelativePaths()
-	return p.gitOps.ApplySkipWorktreeFlags(quotedPaths)
+	return p.gitOps.ApplySkipWorktreeFlagsToFiles(trackedFiles)
 }
@@ -0,0 +1,90 @@
+package protect
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/majikmate/assignment-pull-request/internal/regex"
+	"github.com/majikmate/assignment-pull-request/internal/testutil"
+)
+
+func TestApplyUmaskSnapshotModes(t *testing.T) {
+	dir := testutil.InitRepo(t, testutil.Files("prot/sub/file.txt"))
+	processor := New(dir)
+	pattern := regex.NewWithPatterns([]string{"^prot$"})
+
+	info, err := processor.findProtectedPaths(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := syscall.Umask(0)
+	syscall.Umask(previous)
+
+	mask := 0027
+	restore := applyUmask(&mask)
+	stageDir, err := processor.buildSnapshotFromHEAD(info)
+	restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(stageDir)
+
+	if current := syscall.Umask(previous); current != previous {
+		t.Errorf("umask after restore = %04o, want %04o", current, previous)
+	}
+
+	wantModes := map[string]os.FileMode{
+		"prot/sub":          0750,
+		"prot/sub/file.txt": 0640,
+	}
+	for name, want := range wantModes {
+		fileInfo, err := os.Stat(filepath.Join(stageDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fileInfo.Mode().Perm(); got != want {
+			t.Errorf("mode of %s = %04o, want %04o", name, got, want)
+		}
+	}
+}
+
+func TestApplyUmaskNilLeavesUmask(t *testing.T) {
+	previous := syscall.Umask(0022)
+	defer syscall.Umask(previous)
+
+	restore := applyUmask(nil)
+	if current := syscall.Umask(0022); current != 0022 {
+		t.Errorf("umask = %04o, want unchanged 0022", current)
+	}
+	restore()
+}
+
+func TestFilterTrackedPathsComparesAgainstHEAD(t *testing.T) {
+	dir := testutil.InitRepo(t, testutil.Files("prot/a/x", "removed.md"))
+	testutil.WriteFiles(t, dir, testutil.Files("prot/new/untracked", "untracked.md", "staged.md"))
+	testutil.RunGit(t, dir, "add", "staged.md")
+	testutil.RunGit(t, dir, "rm", "-q", "--cached", "removed.md")
+
+	processor := NewWithOptions(dir, Options{TrackedOnly: true})
+	pattern := regex.NewWithPatterns([]string{"^prot$", `^prot/new$`, `\.md$`})
+	info, err := processor.findProtectedPaths(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headFiles, err := processor.listHEADFiles(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filtered := filterTrackedPaths(info, headFiles)
+
+	got := strings.Join(filtered.RelativePaths(), ",")
+	if want := "prot,removed.md"; got != want {
+		t.Errorf("filterTrackedPaths() = %q, want %q", got, want)
+	}
+}
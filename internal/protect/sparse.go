@@ -0,0 +1,101 @@
+package protect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/majikmate/assignment-pull-request/internal/paths"
+)
+
+// sparseCheckout describes which tracked files sparse-checkout keeps out of the working tree
+type sparseCheckout struct {
+	enabled  bool
+	excluded map[string]bool
+}
+
+// readSparseCheckout determines the sparse-excluded files from the index
+//
+// Git marks sparse-excluded entries skip-worktree. While sparse-checkout is enabled, git also
+// clears that flag from every file present in the working tree, so protect does not set it in
+// that mode (see applySkipWorktreeFlags) and the flag reliably identifies sparse-excluded files,
+// including ones a student deleted afterward, which keep no flag and are restored.
+func (p *Processor) readSparseCheckout() (*sparseCheckout, error) {
+	state := &sparseCheckout{excluded: make(map[string]bool)}
+
+	enabled, err := p.gitOps.IsSparseCheckoutEnabled()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sparse-checkout state: %w", err)
+	}
+	if !enabled {
+		return state, nil
+	}
+	state.enabled = true
+
+	skipped, err := p.gitOps.ListSkipWorktree()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range skipped {
+		state.excluded[file] = true
+	}
+
+	return state, nil
+}
+
+// isExcluded reports whether a tracked file (slash-separated, relative to the repository root)
+// is kept out of the working tree by sparse-checkout
+func (s *sparseCheckout) isExcluded(file string) bool {
+	return s.excluded[file]
+}
+
+// reconcileSparseCheckout splits the tracked files under protected paths into those that are
+// materialized in the working tree and those excluded by sparse-checkout
+//
+// Protected and sparse-checkout patterns are configured independently, so a protected directory
+// may be partially or fully outside the sparse-checkout. Sparse-excluded files must not be
+// recreated by the sync or touched by skip-worktree updates; they are reported for information only.
+func (p *Processor) reconcileSparseCheckout(protectedPathsInfo *paths.Info, sparse *sparseCheckout) ([]string, []string, error) {
+	trackedFiles, err := p.gitOps.ListTrackedUnder(protectedPathsInfo.RelativePaths())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var included, excluded []string
+	for _, file := range trackedFiles {
+		if sparse.isExcluded(file) {
+			excluded = append(excluded, file)
+		} else {
+			included = append(included, file)
+		}
+	}
+
+	if len(excluded) > 0 {
+		fmt.Printf("  ℹ️  Skipping %d protected file(s) excluded by sparse-checkout:\n", len(excluded))
+		for _, file := range excluded {
+			fmt.Printf("    - %s\n", file)
+		}
+	}
+
+	return included, excluded, nil
+}
+
+// pruneSparseExcluded removes sparse-excluded files from the staging directory so the sync
+// does not materialize them in the working tree
+func pruneSparseExcluded(stageDir string, excluded []string) error {
+	for _, file := range excluded {
+		stagedPath := filepath.Join(stageDir, file)
+		if err := os.Remove(stagedPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to prune sparse-excluded file %s: %w", file, err)
+		}
+
+		// Remove parent directories left empty, stopping at the first non-empty one
+		for dir := filepath.Dir(stagedPath); dir != stageDir && dir != "."; dir = filepath.Dir(dir) {
+			if err := os.Remove(dir); err != nil {
+				break
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package protect
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/majikmate/assignment-pull-request/internal/regex"
+	"github.com/majikmate/assignment-pull-request/internal/testutil"
+)
+
+// stagedFiles returns the slash-separated files below stageDir
+func stagedFiles(t *testing.T, stageDir string) []string {
+	t.Helper()
+
+	var files []string
+	err := filepath.Walk(stageDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			relativePath, _ := filepath.Rel(stageDir, path)
+			files = append(files, filepath.ToSlash(relativePath))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(files)
+	return files
+}
+
+func TestSparseExcludedProtectedDirectory(t *testing.T) {
+	for _, mode := range []string{"--cone", "--no-cone"} {
+		t.Run(strings.TrimPrefix(mode, "--"), func(t *testing.T) {
+			dir := testutil.InitRepo(t, testutil.Files("prot/a/x", "prot/b/y", "prot/top", "other/z"))
+			if mode == "--cone" {
+				testutil.RunGit(t, dir, "sparse-checkout", "set", "--cone", "prot/a", "other")
+			} else {
+				testutil.RunGit(t, dir, "sparse-checkout", "set", "--no-cone", "/prot/a/", "/prot/top", "/other/")
+			}
+			processor := New(dir)
+
+			sparse, err := processor.readSparseCheckout()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sparse.enabled {
+				t.Fatal("sparse-checkout not detected")
+			}
+
+			info, err := processor.findProtectedPaths(regex.NewWithPatterns([]string{"^prot$"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			included, excluded, err := processor.reconcileSparseCheckout(info, sparse)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"prot/a/x", "prot/top"}; !slices.Equal(included, want) {
+				t.Errorf("included = %v, want %v", included, want)
+			}
+			if want := []string{"prot/b/y"}; !slices.Equal(excluded, want) {
+				t.Errorf("excluded = %v, want %v", excluded, want)
+			}
+
+			stageDir, err := processor.buildSnapshotFromHEAD(info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(stageDir)
+
+			if err := pruneSparseExcluded(stageDir, excluded); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := stagedFiles(t, stageDir), []string{"prot/a/x", "prot/top"}; !slices.Equal(got, want) {
+				t.Errorf("staged files = %v, want %v", got, want)
+			}
+			if _, err := os.Stat(filepath.Join(stageDir, "prot", "b")); !os.IsNotExist(err) {
+				t.Errorf("empty sparse-excluded directory left in stage: %v", err)
+			}
+
+			// Skip-worktree flags are left to git while sparse-checkout is enabled
+			if err := processor.applySkipWorktreeFlags(included, sparse); err != nil {
+				t.Fatal(err)
+			}
+			if got := testutil.RunGit(t, dir, "ls-files", "-t", "prot"); got != "H prot/a/x\nS prot/b/y\nH prot/top\n" {
+				t.Errorf("ls-files -t = %q", got)
+			}
+		})
+	}
+}
+
+func TestSparseCheckoutDisabled(t *testing.T) {
+	dir := testutil.InitRepo(t, testutil.Files("prot/a/x"))
+	processor := New(dir)
+
+	sparse, err := processor.readSparseCheckout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sparse.enabled || sparse.isExcluded("prot/a/x") {
+		t.Errorf("sparse-checkout reported for a full checkout: %+v", sparse)
+	}
+
+	if err := processor.applySkipWorktreeFlags([]string{"prot/a/x"}, sparse); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.RunGit(t, dir, "ls-files", "-t"); got != "S prot/a/x\n" {
+		t.Errorf("ls-files -t = %q, want skip-worktree flag", got)
+	}
+}
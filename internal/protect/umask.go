@@ -0,0 +1,22 @@
+package protect
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// applyUmask sets the process umask and returns a function that restores the previous one
+// The umask is process-wide, so the protect flow must not run concurrently with other
+// goroutines that create files and rely on the inherited umask.
+func applyUmask(mask *int) func() {
+	if mask == nil {
+		return func() {}
+	}
+
+	previous := syscall.Umask(*mask)
+	fmt.Printf("  Using umask %04o (previous %04o)\n", *mask, previous)
+
+	return func() {
+		syscall.Umask(previous)
+	}
+}
@@ -0,0 +1,30 @@
+package resolve
+
+import (
+	"fmt"
+
+	"github.com/majikmate/assignment-pull-request/internal/assignment"
+	"github.com/majikmate/assignment-pull-request/internal/paths"
+	"github.com/majikmate/assignment-pull-request/internal/protect"
+	"github.com/majikmate/assignment-pull-request/internal/regex"
+)
+
+// Paths resolves the assignment folders and protected paths under repositoryRoot in a single
+// walk of the directory tree, using the same search options as the assignment and protect
+// processors. Each result only contains paths matching its own patterns; a pattern set without
+// patterns yields an empty result.
+//
+// The results are the raw walk output only. Steps applied later by protect.ProtectPaths, such as
+// restoring protected paths missing from the working tree or limiting protection to tracked
+// files, are not reflected in the protected result.
+func Paths(repositoryRoot string, assignmentPattern, protectedPathsPattern *regex.Processor) (*paths.Info, *paths.Info, error) {
+	results, err := paths.FindAll(repositoryRoot,
+		paths.Query{Patterns: assignmentPattern, Options: assignment.SearchOptions()},
+		paths.Query{Patterns: protectedPathsPattern, Options: protect.SearchOptions()},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve paths: %w", err)
+	}
+
+	return results[0], results[1], nil
+}
@@ -0,0 +1,68 @@
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// InitRepo creates a git repository on main and commits the given files, keyed by relative path
+func InitRepo(t testing.TB, files map[string]string) string {
+	t.Helper()
+
+	dir := InitUnbornRepo(t)
+	WriteFiles(t, dir, files)
+	RunGit(t, dir, "add", "-A")
+	RunGit(t, dir, "commit", "-q", "--allow-empty", "-m", "initial")
+	return dir
+}
+
+// InitUnbornRepo creates a git repository on main with a test identity but without any commits
+func InitUnbornRepo(t testing.TB) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	RunGit(t, dir, "init", "-q", "-b", "main")
+	RunGit(t, dir, "config", "user.email", "test@example.com")
+	RunGit(t, dir, "config", "user.name", "test")
+	return dir
+}
+
+// Files maps each relative path to its own name as content
+func Files(names ...string) map[string]string {
+	files := make(map[string]string, len(names))
+	for _, name := range names {
+		files[name] = name
+	}
+	return files
+}
+
+// WriteFiles writes the given files below dir, creating parent directories as needed
+func WriteFiles(t testing.TB, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// RunGit runs git in dir and returns its combined output, failing the test on error
+func RunGit(t testing.TB, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return string(output)
+}
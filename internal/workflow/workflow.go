@@ -5,6 +5,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/majikmate/assignment-pull-request/internal/constants"
@@ -36,6 +37,7 @@ type Step struct {
 type Processor struct {
 	assignmentPattern       *regex.Processor
 	protectedFoldersPattern *regex.Processor
+	lockedBranches          []string
 }
 
 // New creates a new workflow processor
@@ -56,6 +58,11 @@ func (p *Processor) ProtectedPathsPattern() *regex.Processor {
 	return p.protectedFoldersPattern
 }
 
+// LockedBranches returns the branch names that are closed to further commits and pushes
+func (p *Processor) LockedBranches() []string {
+	return p.lockedBranches
+}
+
 // ParseAllFiles finds and parses all workflow files
 func (p *Processor) ParseAllFiles() error {
 	workflowFiles, err := p.findFiles()
@@ -156,44 +163,54 @@ func (p *Processor) parseFile(filePath string) error {
 	for _, job := range config.Jobs {
 		// Case 1: Reusable workflow at job level
 		if p.isAssignmentAction(job.Uses) {
-			if with := job.With; with != nil {
-				// Extract assignment patterns
-				if assignmentPatterns, ok := with[constants.WorkflowAssignmentRegexKey]; ok {
-					if assignmentStr, ok := assignmentPatterns.(string); ok {
-						p.assignmentPattern.AddNewlineSeparated(assignmentStr)
-					}
-				}
-
-				// Extract protected paths patterns
-				if protectedPatterns, ok := with[constants.WorkflowProtectedPathsRegexKey]; ok {
-					if protectedStr, ok := protectedPatterns.(string); ok {
-						p.protectedFoldersPattern.AddNewlineSeparated(protectedStr)
-					}
-				}
-			}
+			p.extractInputs(job.With)
 		}
 
 		// Case 2: Steps within job
 		for _, step := range job.Steps {
 			if p.isAssignmentAction(step.Uses) {
-				if with := step.With; with != nil {
-					// Extract assignment patterns
-					if assignmentPatterns, ok := with[constants.WorkflowAssignmentRegexKey]; ok {
-						if assignmentStr, ok := assignmentPatterns.(string); ok {
-							p.assignmentPattern.AddNewlineSeparated(assignmentStr)
-						}
-					}
-
-					// Extract protected paths patterns
-					if protectedPatterns, ok := with[constants.WorkflowProtectedPathsRegexKey]; ok {
-						if protectedStr, ok := protectedPatterns.(string); ok {
-							p.protectedFoldersPattern.AddNewlineSeparated(protectedStr)
-						}
-					}
-				}
+				p.extractInputs(step.With)
 			}
 		}
 	}
 
 	return nil
 }
+
+// extractInputs collects patterns and settings from the "with" inputs of an assignment action
+func (p *Processor) extractInputs(with map[string]interface{}) {
+	if with == nil {
+		return
+	}
+
+	// Extract assignment patterns
+	if assignmentPatterns, ok := with[constants.WorkflowAssignmentRegexKey]; ok {
+		if assignmentStr, ok := assignmentPatterns.(string); ok {
+			p.assignmentPattern.AddNewlineSeparated(assignmentStr)
+		}
+	}
+
+	// Extract protected paths patterns
+	if protectedPatterns, ok := with[constants.WorkflowProtectedPathsRegexKey]; ok {
+		if protectedStr, ok := protectedPatterns.(string); ok {
+			p.protectedFoldersPattern.AddNewlineSeparated(protectedStr)
+		}
+	}
+
+	// Extract locked branch names
+	if lockedBranches, ok := with[constants.WorkflowLockedBranchesKey]; ok {
+		if lockedStr, ok := lockedBranches.(string); ok {
+			p.addLockedBranches(lockedStr)
+		}
+	}
+}
+
+// addLockedBranches adds newline-separated branch names with deduplication
+func (p *Processor) addLockedBranches(branches string) {
+	for _, line := range strings.Split(branches, "\n") {
+		branch := strings.TrimSpace(line)
+		if branch != "" && !slices.Contains(p.lockedBranches, branch) {
+			p.lockedBranches = append(p.lockedBranches, branch)
+		}
+	}
+}
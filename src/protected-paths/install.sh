@@ -72,9 +72,9 @@ EOF
     sudo chmod 755 /etc/git/hooks/githook-rsync
 fi
 
-# --- Create symbolic links for all post-* hooks ---
+# --- Create symbolic links for all post-* and gating pre-* hooks ---
 echo "🔗 Creating hook symlinks..."
-for hook in post-checkout post-merge post-rewrite post-applypatch post-commit post-reset; do
+for hook in post-checkout post-merge post-rewrite post-applypatch post-commit post-reset pre-commit pre-push; do
     sudo ln -sf protect-sync-hook "/etc/git/hooks/$hook"
     echo "   Linked $hook -> protect-sync-hook"
 done
@@ -10,7 +10,7 @@ echo "🧹 Cleaning up Assignment Pull Request git hooks installation..."
 
 # --- Remove git hooks and related files ---
 echo "🗑️  Removing git hooks..."
-sudo rm -f /etc/git/hooks/post-* /etc/git/hooks/protect-sync-hook /etc/git/hooks/githook-rsync 2>/dev/null || true
+sudo rm -f /etc/git/hooks/post-* /etc/git/hooks/pre-commit /etc/git/hooks/pre-push /etc/git/hooks/protect-sync-hook /etc/git/hooks/githook-rsync 2>/dev/null || true
 echo "   Removed all hook files"
 
 # --- Remove git hooks directories if empty ---